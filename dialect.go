@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Dialect abstracts the SQL differences between database backends so the
+// schema and query-building code in database can stay backend-agnostic.
+// Implementations exist for Postgres, MySQL, and SQLite; NewDatabase picks
+// one by the driver argument. Only the "postgres" dialect currently has a
+// live connection behind it (see Connect) — the others render SQL a caller
+// can run through their own driver of choice until this module grows a
+// native database/sql connection path for them.
+type Dialect interface {
+	// MapType renders a Go reflect.Kind (and, for sized types, a size) as
+	// this dialect's column type, e.g. "VARCHAR(255)" or "TEXT".
+	MapType(kind reflect.Kind, size int) string
+	// Quote renders ident as a safely quoted identifier for this dialect.
+	Quote(ident string) string
+	// Placeholder renders the n'th (1-indexed) bound-parameter placeholder.
+	Placeholder(n int) string
+	// CreateTableSQL renders a CREATE TABLE statement for name from
+	// already-rendered column definitions.
+	CreateTableSQL(name string, columns []string) string
+	// AutoIncrementPK renders this dialect's auto-incrementing primary key
+	// column type, e.g. "SERIAL" or "INT AUTO_INCREMENT".
+	AutoIncrementPK() string
+}
+
+// postgresDialect targets PostgreSQL via pgx.
+type postgresDialect struct{}
+
+func (postgresDialect) MapType(kind reflect.Kind, size int) string {
+	switch kind {
+	case reflect.String:
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	case reflect.Int8, reflect.Int16:
+		return "SMALLINT"
+	case reflect.Int32:
+		return "INTEGER"
+	case reflect.Int64, reflect.Int:
+		return "BIGINT"
+	case reflect.Float32:
+		return "FLOAT4"
+	case reflect.Float64:
+		return "FLOAT8"
+	case reflect.Bool:
+		return "BOOLEAN"
+	default:
+		return ""
+	}
+}
+func (postgresDialect) Quote(ident string) string { return quoteWith(ident, `"`) }
+func (postgresDialect) Placeholder(n int) string  { return fmt.Sprintf("$%d", n) }
+func (postgresDialect) AutoIncrementPK() string   { return "SERIAL" }
+func (postgresDialect) CreateTableSQL(name string, columns []string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", name, joinColumns(columns))
+}
+
+// mysqlDialect renders MySQL SQL; a caller would run it through
+// database/sql + go-sql-driver/mysql, but this module doesn't open that
+// connection itself yet (see the Dialect doc comment).
+type mysqlDialect struct{}
+
+func (mysqlDialect) MapType(kind reflect.Kind, size int) string {
+	switch kind {
+	case reflect.String:
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	case reflect.Int8, reflect.Int16:
+		return "SMALLINT"
+	case reflect.Int32:
+		return "INT"
+	case reflect.Int64, reflect.Int:
+		return "BIGINT"
+	case reflect.Float32:
+		return "FLOAT"
+	case reflect.Float64:
+		return "DOUBLE"
+	case reflect.Bool:
+		return "TINYINT(1)"
+	default:
+		return ""
+	}
+}
+func (mysqlDialect) Quote(ident string) string { return quoteWith(ident, "`") }
+func (mysqlDialect) Placeholder(int) string    { return "?" }
+func (mysqlDialect) AutoIncrementPK() string   { return "INT AUTO_INCREMENT" }
+func (mysqlDialect) CreateTableSQL(name string, columns []string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", name, joinColumns(columns))
+}
+
+// sqliteDialect targets SQLite.
+type sqliteDialect struct{}
+
+func (sqliteDialect) MapType(kind reflect.Kind, size int) string {
+	switch kind {
+	case reflect.String:
+		return "TEXT"
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	case reflect.Bool:
+		return "BOOLEAN"
+	default:
+		return ""
+	}
+}
+func (sqliteDialect) Quote(ident string) string { return quoteWith(ident, `"`) }
+func (sqliteDialect) Placeholder(int) string    { return "?" }
+func (sqliteDialect) AutoIncrementPK() string   { return "INTEGER PRIMARY KEY AUTOINCREMENT" }
+func (sqliteDialect) CreateTableSQL(name string, columns []string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", name, joinColumns(columns))
+}
+
+func joinColumns(columns []string) string {
+	joined := ""
+	for i, column := range columns {
+		if i > 0 {
+			joined += ", "
+		}
+		joined += column
+	}
+	return joined
+}
+
+// quoteWith wraps ident in quote, doubling any quote character inside it.
+func quoteWith(ident, quote string) string {
+	return quote + strings.ReplaceAll(ident, quote, quote+quote) + quote
+}
+
+// isIntegerKind reports whether kind is one of the integer reflect.Kinds,
+// used to decide whether a primary key column gets a dialect's
+// auto-incrementing column type.
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isFloatKind reports whether kind is one of the floating-point
+// reflect.Kinds.
+func isFloatKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// dialectForDriver resolves a driver name ("postgres", "mysql", "sqlite")
+// to its Dialect implementation.
+func dialectForDriver(driver string) (Dialect, error) {
+	switch driver {
+	case "postgres", "":
+		return postgresDialect{}, nil
+	case "mysql":
+		return mysqlDialect{}, nil
+	case "sqlite":
+		return sqliteDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unknown driver %q", driver)
+	}
+}
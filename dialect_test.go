@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDialectMapType(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		kind    reflect.Kind
+		size    int
+		want    string
+	}{
+		{postgresDialect{}, reflect.String, 64, "VARCHAR(64)"},
+		{postgresDialect{}, reflect.Int64, 0, "BIGINT"},
+		{postgresDialect{}, reflect.Bool, 0, "BOOLEAN"},
+		{mysqlDialect{}, reflect.String, 64, "VARCHAR(64)"},
+		{mysqlDialect{}, reflect.Int32, 0, "INT"},
+		{mysqlDialect{}, reflect.Bool, 0, "TINYINT(1)"},
+		{sqliteDialect{}, reflect.String, 64, "TEXT"},
+		{sqliteDialect{}, reflect.Int64, 0, "INTEGER"},
+		{sqliteDialect{}, reflect.Bool, 0, "BOOLEAN"},
+	}
+	for _, c := range cases {
+		if got := c.dialect.MapType(c.kind, c.size); got != c.want {
+			t.Errorf("%T.MapType(%v, %d) = %q, want %q", c.dialect, c.kind, c.size, got, c.want)
+		}
+	}
+}
+
+func TestDialectMapTypeUnknownKind(t *testing.T) {
+	for _, dialect := range []Dialect{postgresDialect{}, mysqlDialect{}, sqliteDialect{}} {
+		if got := dialect.MapType(reflect.Slice, 0); got != "" {
+			t.Errorf("%T.MapType(Slice, 0) = %q, want empty string", dialect, got)
+		}
+	}
+}
+
+func TestDialectPlaceholderAndQuote(t *testing.T) {
+	if got := (postgresDialect{}).Placeholder(3); got != "$3" {
+		t.Errorf("postgres Placeholder(3) = %q, want %q", got, "$3")
+	}
+	if got := (mysqlDialect{}).Placeholder(3); got != "?" {
+		t.Errorf("mysql Placeholder(3) = %q, want %q", got, "?")
+	}
+	if got := (mysqlDialect{}).Quote("users"); got != "`users`" {
+		t.Errorf("mysql Quote(users) = %q, want %q", got, "`users`")
+	}
+	if got := (postgresDialect{}).Quote("users"); got != `"users"` {
+		t.Errorf("postgres Quote(users) = %q, want %q", got, `"users"`)
+	}
+}
+
+func TestIsIntegerKind(t *testing.T) {
+	for _, kind := range []reflect.Kind{reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64} {
+		if !isIntegerKind(kind) {
+			t.Errorf("isIntegerKind(%v) = false, want true", kind)
+		}
+	}
+	for _, kind := range []reflect.Kind{reflect.String, reflect.Float64, reflect.Bool} {
+		if isIntegerKind(kind) {
+			t.Errorf("isIntegerKind(%v) = true, want false", kind)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// pgTypeToKind is the inverse of postgresDialect.MapType, used to recover a
+// reflect.Kind from a column's reported PostgreSQL data type when
+// reconstructing a databaseTable from a live schema.
+var pgTypeToKind = map[string]reflect.Kind{
+	"character varying": reflect.String,
+	"text":              reflect.String,
+	"smallint":          reflect.Int16,
+	"integer":           reflect.Int32,
+	"bigint":            reflect.Int64,
+	"real":              reflect.Float32,
+	"double precision":  reflect.Float64,
+	"boolean":           reflect.Bool,
+}
+
+// Introspect reconstructs db.tables by querying information_schema.columns
+// and pg_catalog.pg_constraint for the given schema (typically "public"),
+// discovering primary keys, uniques, and not-null constraints for every
+// table it finds. This lets the module be pointed at a pre-existing
+// database without the caller transcribing its schema by hand.
+func (db *database) Introspect(schema string) (map[string]databaseTable, error) {
+	if db.connection == nil {
+		return nil, errors.New("database is not connected")
+	}
+	rows, err := db.connection.Query(context.Background(),
+		`SELECT c.table_name, c.column_name, c.data_type, c.character_maximum_length, c.is_nullable
+		 FROM information_schema.columns c
+		 WHERE c.table_schema = $1
+		 ORDER BY c.table_name, c.ordinal_position`, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string]databaseTable)
+	for rows.Next() {
+		var tableName, columnName, dataType, isNullable string
+		var maxLength *int
+		if err := rows.Scan(&tableName, &columnName, &dataType, &maxLength, &isNullable); err != nil {
+			return nil, err
+		}
+		kind, known := pgTypeToKind[dataType]
+		if !known {
+			continue
+		}
+		size := 255
+		if maxLength != nil {
+			size = *maxLength
+		}
+		table, exists := tables[tableName]
+		if !exists {
+			table = databaseTable{columns: make(map[string]databaseTableColumn)}
+		}
+		table.columns[columnName] = databaseTableColumn{
+			columnType:  databaseTableColumnType{columnType: kind, size: size},
+			constraints: databaseTableColumnConstraints{notNull: isNullable == "NO"},
+		}
+		// Rows are returned ordered by ordinal_position, so appending here
+		// preserves the table's live column order.
+		table.columnOrder = append(table.columnOrder, columnName)
+		tables[tableName] = table
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := db.introspectConstraints(schema, tables); err != nil {
+		return nil, err
+	}
+
+	db.tables = tables
+	return tables, nil
+}
+
+// introspectConstraints fills in primary key, unique, and foreign key flags
+// on an already column-populated tables map by reading pg_catalog.pg_constraint.
+func (db *database) introspectConstraints(schema string, tables map[string]databaseTable) error {
+	rows, err := db.connection.Query(context.Background(),
+		`SELECT cl.relname AS table_name, a.attname AS column_name, con.contype
+		 FROM pg_catalog.pg_constraint con
+		 JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+		 JOIN pg_catalog.pg_namespace n ON n.oid = cl.relnamespace
+		 JOIN unnest(con.conkey) AS colnum ON true
+		 JOIN pg_catalog.pg_attribute a ON a.attrelid = cl.oid AND a.attnum = colnum
+		 WHERE n.nspname = $1`, schema)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, columnName, contype string
+		if err := rows.Scan(&tableName, &columnName, &contype); err != nil {
+			return err
+		}
+		table, exists := tables[tableName]
+		if !exists {
+			continue
+		}
+		column, exists := table.columns[columnName]
+		if !exists {
+			continue
+		}
+		switch contype {
+		case "p":
+			column.constraints.primaryKey = true
+		case "u":
+			column.constraints.unique = true
+		case "f":
+			column.constraints.foreignKey = true
+		case "c":
+			column.constraints.check = true
+		}
+		table.columns[columnName] = column
+	}
+	return rows.Err()
+}
+
+// GenerateModels writes one Go source file per discovered table into
+// outDir, each containing a struct tagged for RegisterModel/Migrate plus
+// Insert/Get/Update/Delete methods built on the existing pgxpool connection.
+// Call Introspect first so db.tables reflects the live schema.
+func (db *database) GenerateModels(outDir string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	for tableName, table := range db.tables {
+		source, err := generateModelSource(tableName, table)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(outDir, tableName+".go")
+		if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// modelColumn is one field of a generated model struct, in the deterministic
+// order generateModelSource emits them.
+type modelColumn struct {
+	columnName string
+	fieldName  string
+	goType     string
+}
+
+func generateModelSource(tableName string, table databaseTable) (string, error) {
+	structName := strings.Title(tableName)
+
+	var columnNames []string
+	for name := range table.columns {
+		columnNames = append(columnNames, name)
+	}
+	sort.Strings(columnNames)
+
+	var columns []modelColumn
+	var fields []string
+	var pkColumn *modelColumn
+	for _, name := range columnNames {
+		column := table.columns[name]
+		goType, err := kindToGoType(column.columnType.columnType)
+		if err != nil {
+			return "", err
+		}
+		field := modelColumn{columnName: name, fieldName: strings.Title(name), goType: goType}
+		columns = append(columns, field)
+		fields = append(fields, fmt.Sprintf("\t%s %s `db:\"%s\"`", field.fieldName, field.goType, field.columnName))
+		if column.constraints.primaryKey && pkColumn == nil {
+			pk := field
+			pkColumn = &pk
+		}
+	}
+	// Fall back to the table's first column (in live ordinal_position
+	// order, not the alphabetical order columns are emitted in) as the row
+	// identifier for Get/Update/Delete when introspection found no declared
+	// primary key.
+	if pkColumn == nil && len(columns) > 0 {
+		firstName := columnNames[0]
+		if len(table.columnOrder) > 0 {
+			firstName = table.columnOrder[0]
+		}
+		for i := range columns {
+			if columns[i].columnName == firstName {
+				pk := columns[i]
+				pkColumn = &pk
+				break
+			}
+		}
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "package models\n\n")
+	fmt.Fprintf(&builder, "import (\n\t\"context\"\n\n\t\"github.com/jackc/pgx/v5/pgxpool\"\n)\n\n")
+	fmt.Fprintf(&builder, "// %s was generated by database.GenerateModels from table %q.\n", structName, tableName)
+	fmt.Fprintf(&builder, "type %s struct {\n%s\n}\n", structName, strings.Join(fields, "\n"))
+
+	if pkColumn != nil {
+		fmt.Fprintf(&builder, "\nfunc (row %s) PrimaryKey() any { return row.%s }\n", structName, pkColumn.fieldName)
+	}
+
+	writeInsert(&builder, structName, tableName, columns)
+	if pkColumn != nil {
+		writeGet(&builder, structName, tableName, columns, *pkColumn)
+		writeUpdate(&builder, structName, tableName, columns, *pkColumn)
+		writeDelete(&builder, structName, tableName, *pkColumn)
+	}
+	return builder.String(), nil
+}
+
+// writeInsert emits an Insert method that inserts every column of row.
+func writeInsert(builder *strings.Builder, structName, tableName string, columns []modelColumn) {
+	var names, placeholders, values []string
+	for i, column := range columns {
+		names = append(names, column.columnName)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		values = append(values, "row."+column.fieldName)
+	}
+	fmt.Fprintf(builder, "\nfunc (row %s) Insert(ctx context.Context, pool *pgxpool.Pool) error {\n", structName)
+	fmt.Fprintf(builder, "\t_, err := pool.Exec(ctx, \"INSERT INTO %s (%s) VALUES (%s)\", %s)\n",
+		tableName, strings.Join(names, ","), strings.Join(placeholders, ","), strings.Join(values, ", "))
+	fmt.Fprintf(builder, "\treturn err\n}\n")
+}
+
+// writeGet emits a Get<StructName> function that looks up one row by pk.
+func writeGet(builder *strings.Builder, structName, tableName string, columns []modelColumn, pk modelColumn) {
+	var names, scanTargets []string
+	for _, column := range columns {
+		names = append(names, column.columnName)
+		scanTargets = append(scanTargets, "&row."+column.fieldName)
+	}
+	fmt.Fprintf(builder, "\nfunc Get%s(ctx context.Context, pool *pgxpool.Pool, %s %s) (%s, error) {\n",
+		structName, pk.fieldName, pk.goType, structName)
+	fmt.Fprintf(builder, "\tvar row %s\n", structName)
+	fmt.Fprintf(builder, "\terr := pool.QueryRow(ctx, \"SELECT %s FROM %s WHERE %s = $1\", %s).Scan(%s)\n",
+		strings.Join(names, ","), tableName, pk.columnName, pk.fieldName, strings.Join(scanTargets, ", "))
+	fmt.Fprintf(builder, "\treturn row, err\n}\n")
+}
+
+// writeUpdate emits an Update method that sets every non-pk column of row.
+func writeUpdate(builder *strings.Builder, structName, tableName string, columns []modelColumn, pk modelColumn) {
+	var setClauses, values []string
+	for _, column := range columns {
+		if column.columnName == pk.columnName {
+			continue
+		}
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column.columnName, len(setClauses)+1))
+		values = append(values, "row."+column.fieldName)
+	}
+	values = append(values, "row."+pk.fieldName)
+	fmt.Fprintf(builder, "\nfunc (row %s) Update(ctx context.Context, pool *pgxpool.Pool) error {\n", structName)
+	fmt.Fprintf(builder, "\t_, err := pool.Exec(ctx, \"UPDATE %s SET %s WHERE %s = $%d\", %s)\n",
+		tableName, strings.Join(setClauses, ", "), pk.columnName, len(setClauses)+1, strings.Join(values, ", "))
+	fmt.Fprintf(builder, "\treturn err\n}\n")
+}
+
+// writeDelete emits a Delete method that removes row by pk.
+func writeDelete(builder *strings.Builder, structName, tableName string, pk modelColumn) {
+	fmt.Fprintf(builder, "\nfunc (row %s) Delete(ctx context.Context, pool *pgxpool.Pool) error {\n", structName)
+	fmt.Fprintf(builder, "\t_, err := pool.Exec(ctx, \"DELETE FROM %s WHERE %s = $1\", row.%s)\n",
+		tableName, pk.columnName, pk.fieldName)
+	fmt.Fprintf(builder, "\treturn err\n}\n")
+}
+
+func kindToGoType(kind reflect.Kind) (string, error) {
+	switch kind {
+	case reflect.String:
+		return "string", nil
+	case reflect.Int16:
+		return "int16", nil
+	case reflect.Int32:
+		return "int32", nil
+	case reflect.Int64, reflect.Int:
+		return "int64", nil
+	case reflect.Float32:
+		return "float32", nil
+	case reflect.Float64:
+		return "float64", nil
+	case reflect.Bool:
+		return "bool", nil
+	default:
+		return "", fmt.Errorf("no Go type for column kind %v", kind)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateModelSourceIncludesCRUDMethods(t *testing.T) {
+	table := databaseTable{
+		columns: map[string]databaseTableColumn{
+			"id": {
+				columnType:  databaseTableColumnType{columnType: reflect.String, size: 255},
+				constraints: databaseTableColumnConstraints{primaryKey: true},
+			},
+			"username": {
+				columnType: databaseTableColumnType{columnType: reflect.String, size: 255},
+			},
+		},
+	}
+	source, err := generateModelSource("users", table)
+	if err != nil {
+		t.Fatalf("generateModelSource returned error: %v", err)
+	}
+	for _, want := range []string{
+		"type Users struct",
+		"func (row Users) Insert(ctx context.Context, pool *pgxpool.Pool) error",
+		"func GetUsers(ctx context.Context, pool *pgxpool.Pool, Id string) (Users, error)",
+		"func (row Users) Update(ctx context.Context, pool *pgxpool.Pool) error",
+		"func (row Users) Delete(ctx context.Context, pool *pgxpool.Pool) error",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("generated source missing %q\n---\n%s", want, source)
+		}
+	}
+}
+
+func TestGenerateModelSourceWithoutPrimaryKeyFallsBack(t *testing.T) {
+	table := databaseTable{
+		columns: map[string]databaseTableColumn{
+			"name": {columnType: databaseTableColumnType{columnType: reflect.String, size: 255}},
+		},
+	}
+	source, err := generateModelSource("tags", table)
+	if err != nil {
+		t.Fatalf("generateModelSource returned error: %v", err)
+	}
+	if !strings.Contains(source, "func GetTags(ctx context.Context, pool *pgxpool.Pool, Name string) (Tags, error)") {
+		t.Errorf("expected Get method keyed on the sole column, got:\n%s", source)
+	}
+}
+
+func TestGenerateModelSourceFallsBackToOrdinalFirstColumn(t *testing.T) {
+	table := databaseTable{
+		columns: map[string]databaseTableColumn{
+			"name":       {columnType: databaseTableColumnType{columnType: reflect.String, size: 255}},
+			"weight":     {columnType: databaseTableColumnType{columnType: reflect.Int32}},
+			"created_at": {columnType: databaseTableColumnType{columnType: reflect.String, size: 255}},
+		},
+		columnOrder: []string{"name", "weight", "created_at"},
+	}
+	source, err := generateModelSource("tags", table)
+	if err != nil {
+		t.Fatalf("generateModelSource returned error: %v", err)
+	}
+	if !strings.Contains(source, "func GetTags(ctx context.Context, pool *pgxpool.Pool, Name string) (Tags, error)") {
+		t.Errorf("expected Get method keyed on the ordinal-first column %q, got:\n%s", "name", source)
+	}
+}
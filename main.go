@@ -5,9 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strconv"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	//"golang.org/x/crypto/argon2"
 	//"net/http"
@@ -30,11 +30,22 @@ type database struct {
 	databaseName string
 	user         string
 	password     string
+	driver       string
+	dialect      Dialect
 	connection   *pgxpool.Pool
 	tables       map[string]databaseTable
+	migrations   []migration
+	// DryRun, when set, makes MigrateUp/MigrateDown print each migration
+	// they would run (its SQL, for directory-loaded ones) instead of
+	// running it.
+	DryRun bool
 }
 type databaseTable struct {
 	columns map[string]databaseTableColumn
+	// columnOrder records column names in their live ordinal_position order,
+	// as reported by Introspect. It is empty for tables built by
+	// tableFromModel, where no such ordering is tracked.
+	columnOrder []string
 }
 type databaseTableColumn struct {
 	columnType  databaseTableColumnType
@@ -50,6 +61,12 @@ type databaseTableColumnConstraints struct {
 	primaryKey bool
 	check      bool
 	foreignKey bool
+	// checkExpression is the CHECK constraint's expression, e.g. "price > 0",
+	// set when check is true.
+	checkExpression string
+	// foreignKeyReference is the referenced "table.column", e.g.
+	// "accounts.id", set when foreignKey is true.
+	foreignKeyReference string
 }
 
 // Database methods, ActionObjectSpec
@@ -58,31 +75,23 @@ func (column databaseTableColumn) asString() (string, error) {
 	data := fmt.Sprintf("Column of type %s and size %d", column.columnType.columnType, column.columnType.size)
 	return data, nil
 }
-func NewDatabase(address, dbname, dbuser, dbpassword string) database {
-	newDB := database{address: address, databaseName: dbname, user: dbuser, password: dbpassword}
-	newDB.tables = make(map[string]databaseTable)
-	return newDB
-}
-func mapTypeToPGTypeString(variableType reflect.Kind) (string, error) {
-	databaseTypesMap := map[reflect.Kind]string{
-		reflect.String:  "VARCHAR",
-		reflect.Int8:    "SMALLINT",
-		reflect.Int16:   "SMALLINT",
-		reflect.Int32:   "INTEGER",
-		reflect.Int64:   "BIGINT",
-		reflect.Int:     "BIGINT",
-		reflect.Float32: "FLOAT4",
-		reflect.Float64: "FLOAT8",
-		reflect.Bool:    "BOOLEAN",
-	}
-	pgTypeString, doesExist := databaseTypesMap[variableType]
-	if doesExist {
-		return pgTypeString, nil
-	} else {
-		return "", errors.New("no such type")
+
+// NewDatabase builds a database bound to the given driver ("postgres",
+// "mysql", or "sqlite"; "" defaults to "postgres"), which selects the
+// Dialect used to render schema and query SQL for it.
+func NewDatabase(address, dbname, dbuser, dbpassword, driver string) (database, error) {
+	dialect, err := dialectForDriver(driver)
+	if err != nil {
+		return database{}, err
 	}
+	newDB := database{address: address, databaseName: dbname, user: dbuser, password: dbpassword, driver: driver, dialect: dialect}
+	newDB.tables = make(map[string]databaseTable)
+	return newDB, nil
 }
 func (db *database) Connect() error {
+	if db.driver != "postgres" && db.driver != "" {
+		return fmt.Errorf("driver %q is not connectable yet, only postgres is wired up to a live connection", db.driver)
+	}
 	connectionString := fmt.Sprintf("postgres://%s:%s@%s/%s", db.user, db.password, db.address, db.databaseName)
 	dbpool, err := pgxpool.New(context.Background(), connectionString)
 	if err != nil {
@@ -100,24 +109,41 @@ func (db *database) AddTable(tableName string, table databaseTable) error {
 			constraintsString = concatenate(constraintsString, "NOT NULL")
 		}
 		if constraints.check {
-			constraintsString = concatenate(constraintsString, "CHECK")
+			constraintsString = concatenate(constraintsString, fmt.Sprintf("CHECK (%s)", constraints.checkExpression))
 		}
 		if constraints.foreignKey {
-			constraintsString = concatenate(constraintsString, "FOREIGN KEY")
+			reference := constraints.foreignKeyReference
+			table, column, found := strings.Cut(reference, ".")
+			if !found {
+				table, column = reference, "id"
+			}
+			constraintsString = concatenate(constraintsString, fmt.Sprintf("REFERENCES %s(%s)", db.dialect.Quote(table), db.dialect.Quote(column)))
 		}
 		if constraints.unique {
 			constraintsString = concatenate(constraintsString, "UNIQUE")
 		}
-		if constraints.primaryKey {
-			constraintsString = concatenate(constraintsString, "PRIMARY KEY")
-		}
-		variableTypeString, err := mapTypeToPGTypeString(column.columnType.columnType)
-		if err != nil {
-			fmt.Println(err)
+		// An integer primary key gets the dialect's auto-incrementing column
+		// type (e.g. Postgres SERIAL) instead of its plain integer type;
+		// non-integer primary keys (e.g. a VARCHAR id) keep their declared
+		// type and just get the PRIMARY KEY keyword.
+		var variableTypeString string
+		if constraints.primaryKey && isIntegerKind(column.columnType.columnType) {
+			variableTypeString = db.dialect.AutoIncrementPK()
+			if !strings.Contains(variableTypeString, "PRIMARY KEY") {
+				constraintsString = concatenate(constraintsString, "PRIMARY KEY")
+			}
+		} else {
+			variableTypeString = db.dialect.MapType(column.columnType.columnType, column.columnType.size)
+			if variableTypeString == "" {
+				fmt.Println(errors.New("no such type"))
+			}
+			if constraints.primaryKey {
+				constraintsString = concatenate(constraintsString, "PRIMARY KEY")
+			}
 		}
-		columnsSlice = append(columnsSlice, fmt.Sprintf("%s %s(%d) %s", name, variableTypeString, column.columnType.size, constraintsString))
+		columnsSlice = append(columnsSlice, fmt.Sprintf("%s %s %s", db.dialect.Quote(name), variableTypeString, constraintsString))
 	}
-	queryString := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", tableName, strings.Join(columnsSlice, ", "))
+	queryString := db.dialect.CreateTableSQL(db.dialect.Quote(tableName), columnsSlice)
 	if db.connection != nil {
 		_, err := db.connection.Exec(context.Background(), queryString)
 		if err != nil {
@@ -139,80 +165,147 @@ func (db *database) CreateAllTables() {
 	}
 }
 func (db *database) DeleteTable(table string) error {
-	_, err := db.connection.Exec(context.Background(), fmt.Sprintf("DROP TABLE %s;", table))
+	_, err := db.connection.Exec(context.Background(), fmt.Sprintf("DROP TABLE %s;", db.dialect.Quote(table)))
 	return err
 }
 func (db *database) InsertRow(tableName string, values map[string]any) error {
+	if db.connection == nil {
+		return errors.New("database is not connected")
+	}
 	table, doesExist := db.tables[tableName]
 	if !doesExist {
 		return errors.New("table requested for insertion does not exist in the data model")
 	}
 	var insertKeys []string
-	var insertValues []string
+	var placeholders []string
+	var args []any
 	for key, value := range values {
 		column, doesExist := table.columns[key]
 		if !doesExist {
 			return errors.New("column requested for insertion does not exist in the data model")
 		}
-		insertKeys = append(insertKeys, key)
 		// type-check
 		valueType := reflect.TypeOf(value).Kind()
 		if column.columnType.columnType != valueType {
 			return fmt.Errorf("value type requested for insertion is incorrect, tried to insert %v value into %v type column", reflect.TypeOf(value), column.columnType.columnType)
 		}
-		intTypes := map[reflect.Kind]bool{reflect.Int: true, reflect.Int32: true, reflect.Int64: true}
-		floatTypes := map[reflect.Kind]bool{reflect.Float32: true, reflect.Float64: true}
-		stringValue := ""
-		if _, isInt := intTypes[valueType]; isInt {
-			stringValue = strconv.FormatInt(value.(int64), 10)
-		} else if _, isFloat := floatTypes[valueType]; isFloat {
-			stringValue = strconv.FormatFloat(value.(float64), 'e', 20, 64)
-		} else if valueType == reflect.String {
-			stringValue = fmt.Sprintf("'%s'", value.(string))
-		} else if valueType == reflect.Bool {
-			stringValue = strconv.FormatBool(value.(bool))
-		} else {
-			return errors.New("value did not match any type")
-		}
-		insertValues = append(insertValues, stringValue)
+		insertKeys = append(insertKeys, db.dialect.Quote(key))
+		placeholders = append(placeholders, db.dialect.Placeholder(len(args)+1))
+		args = append(args, value)
 	}
-	insertString := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", tableName, strings.Join(insertKeys, ","), strings.Join(insertValues, ","))
-	_, err := db.connection.Exec(context.Background(), insertString)
+	insertString := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", db.dialect.Quote(tableName), strings.Join(insertKeys, ","), strings.Join(placeholders, ","))
+	_, err := db.connection.Exec(context.Background(), insertString, args...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// whereClause builds a parameterized "col1 = $1 AND col2 = $2 ..." fragment
+// (rendered in db's dialect) from a column/value map, appending the bound
+// values to args so callers can keep numbering placeholders across a query
+// (e.g. SET before WHERE).
+func (db *database) whereClause(where map[string]any, args *[]any) string {
+	var conditions []string
+	for key, value := range where {
+		*args = append(*args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = %s", db.dialect.Quote(key), db.dialect.Placeholder(len(*args))))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// SelectRows runs a parameterized SELECT * against tableName, optionally
+// restricted by an equality where clause, and scans each row into a
+// map[string]any keyed by column name via pgx.RowToMap.
+func (db *database) SelectRows(tableName string, where map[string]any) ([]map[string]any, error) {
+	if db.connection == nil {
+		return nil, errors.New("database is not connected")
+	}
+	if _, doesExist := db.tables[tableName]; !doesExist {
+		return nil, errors.New("table requested for selection does not exist in the data model")
+	}
+	var args []any
+	queryString := fmt.Sprintf("SELECT * FROM %s", db.dialect.Quote(tableName))
+	if conditions := db.whereClause(where, &args); conditions != "" {
+		queryString += " WHERE " + conditions
+	}
+	rows, err := db.connection.Query(context.Background(), queryString, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToMap)
+}
+
+// UpdateRow runs a parameterized UPDATE against tableName, setting values
+// for every row matching the equality where clause.
+func (db *database) UpdateRow(tableName string, where map[string]any, values map[string]any) error {
+	if db.connection == nil {
+		return errors.New("database is not connected")
+	}
+	table, doesExist := db.tables[tableName]
+	if !doesExist {
+		return errors.New("table requested for update does not exist in the data model")
+	}
+	var args []any
+	var setClauses []string
+	for key, value := range values {
+		if _, doesExist := table.columns[key]; !doesExist {
+			return errors.New("column requested for update does not exist in the data model")
+		}
+		args = append(args, value)
+		setClauses = append(setClauses, fmt.Sprintf("%s = %s", db.dialect.Quote(key), db.dialect.Placeholder(len(args))))
+	}
+	queryString := fmt.Sprintf("UPDATE %s SET %s", db.dialect.Quote(tableName), strings.Join(setClauses, ", "))
+	if conditions := db.whereClause(where, &args); conditions != "" {
+		queryString += " WHERE " + conditions
+	}
+	_, err := db.connection.Exec(context.Background(), queryString, args...)
+	return err
+}
+
+// DeleteRow runs a parameterized DELETE against tableName, removing every
+// row matching the equality where clause.
+func (db *database) DeleteRow(tableName string, where map[string]any) error {
+	if db.connection == nil {
+		return errors.New("database is not connected")
+	}
+	if _, doesExist := db.tables[tableName]; !doesExist {
+		return errors.New("table requested for deletion does not exist in the data model")
+	}
+	var args []any
+	queryString := fmt.Sprintf("DELETE FROM %s", db.dialect.Quote(tableName))
+	if conditions := db.whereClause(where, &args); conditions != "" {
+		queryString += " WHERE " + conditions
+	}
+	_, err := db.connection.Exec(context.Background(), queryString, args...)
+	return err
+}
+
+// user is the Go model for the "user" table, declared with db tags instead
+// of a hand-built databaseTable literal; see Migrate.
+type user struct {
+	ID       string `db:"id,pk,size=255"`
+	Username string `db:"username,notnull,unique,size=255"`
+	Password string `db:"password,notnull,size=255"`
+}
+
 func main() {
-	myDB := NewDatabase("localhost:5432", "test", "test_admin", "1234")
-	usersTable := databaseTable{
-		columns: map[string]databaseTableColumn{
-			"id": {
-				columnType:  databaseTableColumnType{columnType: reflect.String, size: 255},
-				constraints: databaseTableColumnConstraints{primaryKey: true},
-			},
-			"username": {
-				columnType:  databaseTableColumnType{columnType: reflect.String, size: 255},
-				constraints: databaseTableColumnConstraints{notNull: true, unique: true},
-			},
-			"password": {
-				columnType:  databaseTableColumnType{columnType: reflect.String, size: 255},
-				constraints: databaseTableColumnConstraints{notNull: true},
-			},
-		},
-	}
-	err := myDB.Connect()
+	myDB, err := NewDatabase("localhost:5432", "test", "test_admin", "1234", "postgres")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	err = myDB.Connect()
 	if err != nil {
 		fmt.Println(err)
 	}
-	err = myDB.AddTable("users", usersTable)
+	err = myDB.Migrate(&user{})
 	if err != nil {
 		fmt.Println(err)
 	}
-	err = myDB.InsertRow("users", map[string]any{"id": "2n1kj", "username": "John", "password": "1234"})
+	err = myDB.InsertRow("user", map[string]any{"id": "2n1kj", "username": "John", "password": "1234"})
 	if err != nil {
 		fmt.Println(err)
 	}
-	fmt.Print(usersTable.columns["id"].asString())
+	fmt.Print(myDB.tables["user"].columns["id"].asString())
 }
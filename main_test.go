@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestWhereClause(t *testing.T) {
+	db := &database{dialect: postgresDialect{}}
+	var args []any
+	got := db.whereClause(map[string]any{"username": "alice"}, &args)
+	want := `"username" = $1`
+	if got != want {
+		t.Errorf("whereClause = %q, want %q", got, want)
+	}
+	if len(args) != 1 || args[0] != "alice" {
+		t.Errorf("whereClause args = %v, want [alice]", args)
+	}
+}
+
+func TestWhereClauseEmpty(t *testing.T) {
+	db := &database{dialect: postgresDialect{}}
+	var args []any
+	got := db.whereClause(nil, &args)
+	if got != "" {
+		t.Errorf("whereClause = %q, want empty string", got)
+	}
+}
+
+func TestInsertRowErrorsWhenNotConnected(t *testing.T) {
+	db := &database{dialect: postgresDialect{}}
+	if err := db.InsertRow("users", map[string]any{"username": "alice"}); err == nil {
+		t.Fatal("InsertRow on an unconnected database returned nil error, want one")
+	}
+}
+
+func TestSelectRowsErrorsWhenNotConnected(t *testing.T) {
+	db := &database{dialect: postgresDialect{}}
+	if _, err := db.SelectRows("users", nil); err == nil {
+		t.Fatal("SelectRows on an unconnected database returned nil error, want one")
+	}
+}
+
+func TestUpdateRowErrorsWhenNotConnected(t *testing.T) {
+	db := &database{dialect: postgresDialect{}}
+	if err := db.UpdateRow("users", nil, map[string]any{"username": "alice"}); err == nil {
+		t.Fatal("UpdateRow on an unconnected database returned nil error, want one")
+	}
+}
+
+func TestDeleteRowErrorsWhenNotConnected(t *testing.T) {
+	db := &database{dialect: postgresDialect{}}
+	if err := db.DeleteRow("users", nil); err == nil {
+		t.Fatal("DeleteRow on an unconnected database returned nil error, want one")
+	}
+}
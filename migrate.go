@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseDBTag parses a struct field's `db:"..."` tag into a column name and
+// the constraints/size/fk/check modifiers that follow it, e.g.
+// `db:"price,notnull,size=255,fk=accounts.id,check=price>0"`.
+func parseDBTag(tag string) (name string, constraints databaseTableColumnConstraints, size int, fk string, check string) {
+	parts := strings.Split(tag, ",")
+	if len(parts) == 0 {
+		return "", constraints, 0, "", ""
+	}
+	name = parts[0]
+	for _, part := range parts[1:] {
+		switch {
+		case part == "pk":
+			constraints.primaryKey = true
+		case part == "unique":
+			constraints.unique = true
+		case part == "notnull":
+			constraints.notNull = true
+		case strings.HasPrefix(part, "size="):
+			size, _ = strconv.Atoi(strings.TrimPrefix(part, "size="))
+		case strings.HasPrefix(part, "fk="):
+			fk = strings.TrimPrefix(part, "fk=")
+			constraints.foreignKey = true
+		case strings.HasPrefix(part, "check="):
+			check = strings.TrimPrefix(part, "check=")
+			constraints.check = true
+		}
+	}
+	return name, constraints, size, fk, check
+}
+
+// tableFromModel derives a tableName and databaseTable from a struct via
+// reflection, reading column names and constraints from `db:""` tags and
+// falling back to the lowercased field name when a field has no tag.
+func tableFromModel(model interface{}) (string, databaseTable, error) {
+	value := reflect.ValueOf(model)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return "", databaseTable{}, fmt.Errorf("Migrate/RegisterModel expects a struct, got %v", value.Kind())
+	}
+	structType := value.Type()
+	tableName := strings.ToLower(structType.Name())
+	table := databaseTable{columns: make(map[string]databaseTableColumn)}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag, hasTag := field.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+		name := strings.ToLower(field.Name)
+		var constraints databaseTableColumnConstraints
+		size := 255
+		if hasTag {
+			tagName, tagConstraints, tagSize, fk, check := parseDBTag(tag)
+			if tagName != "" {
+				name = tagName
+			}
+			constraints = tagConstraints
+			constraints.foreignKeyReference = fk
+			constraints.checkExpression = check
+			if tagSize != 0 {
+				size = tagSize
+			}
+		}
+		table.columns[name] = databaseTableColumn{
+			columnType:  databaseTableColumnType{columnType: field.Type.Kind(), size: size},
+			constraints: constraints,
+		}
+	}
+	return tableName, table, nil
+}
+
+// RegisterModel derives a databaseTable from a Go struct and adds it to
+// db.tables without touching the live schema. Use Migrate to also create or
+// evolve the table in PostgreSQL.
+func (db *database) RegisterModel(model interface{}) error {
+	tableName, table, err := tableFromModel(model)
+	if err != nil {
+		return err
+	}
+	db.tables[tableName] = table
+	return nil
+}
+
+// existingColumns queries information_schema.columns for the live column
+// names of tableName in the "public" schema, returning an empty, non-nil
+// set if the table does not exist yet. It's scoped to a single schema the
+// same way Introspect is, since table_name alone is ambiguous across
+// schemas.
+func (db *database) existingColumns(tableName string) (map[string]bool, error) {
+	rows, err := db.connection.Query(context.Background(),
+		"SELECT column_name FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2",
+		"public", tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var columnName string
+		if err := rows.Scan(&columnName); err != nil {
+			return nil, err
+		}
+		columns[columnName] = true
+	}
+	return columns, rows.Err()
+}
+
+// Migrate derives a databaseTable from model and reconciles it against the
+// live PostgreSQL schema: it creates the table if missing, otherwise emits
+// ALTER TABLE ADD COLUMN / DROP COLUMN statements for any drift between the
+// struct and what information_schema reports. Re-running Migrate against an
+// evolved struct evolves the schema safely.
+func (db *database) Migrate(model interface{}) error {
+	if db.connection == nil {
+		return errors.New("database is not connected")
+	}
+	tableName, table, err := tableFromModel(model)
+	if err != nil {
+		return err
+	}
+	existing, err := db.existingColumns(tableName)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return db.AddTable(tableName, table)
+	}
+	for name, column := range table.columns {
+		if existing[name] {
+			continue
+		}
+		variableTypeString := db.dialect.MapType(column.columnType.columnType, column.columnType.size)
+		if variableTypeString == "" {
+			return fmt.Errorf("no such type for column %q", name)
+		}
+		alterString := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", db.dialect.Quote(tableName), db.dialect.Quote(name), variableTypeString)
+		if _, err := db.connection.Exec(context.Background(), alterString); err != nil {
+			return err
+		}
+	}
+	for name := range existing {
+		if _, stillPresent := table.columns[name]; stillPresent {
+			continue
+		}
+		alterString := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", db.dialect.Quote(tableName), db.dialect.Quote(name))
+		if _, err := db.connection.Exec(context.Background(), alterString); err != nil {
+			return err
+		}
+	}
+	db.tables[tableName] = table
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import "testing"
+
+func TestParseDBTag(t *testing.T) {
+	name, constraints, size, fk, check := parseDBTag("email,unique,notnull,size=255,fk=accounts.id,check=email<>''")
+	if name != "email" {
+		t.Errorf("name = %q, want %q", name, "email")
+	}
+	if !constraints.unique || !constraints.notNull || !constraints.foreignKey || !constraints.check {
+		t.Errorf("constraints = %+v, want unique/notnull/foreignKey/check all true", constraints)
+	}
+	if size != 255 {
+		t.Errorf("size = %d, want 255", size)
+	}
+	if fk != "accounts.id" {
+		t.Errorf("fk = %q, want %q", fk, "accounts.id")
+	}
+	if check != "email<>''" {
+		t.Errorf("check = %q, want %q", check, "email<>''")
+	}
+}
+
+func TestParseDBTagNameOnly(t *testing.T) {
+	name, constraints, size, fk, check := parseDBTag("id")
+	if name != "id" {
+		t.Errorf("name = %q, want %q", name, "id")
+	}
+	if constraints != (databaseTableColumnConstraints{}) {
+		t.Errorf("constraints = %+v, want zero value", constraints)
+	}
+	if size != 0 || fk != "" || check != "" {
+		t.Errorf("size/fk/check = %d/%q/%q, want zero values", size, fk, check)
+	}
+}
+
+type testUser struct {
+	ID    string `db:"id,pk"`
+	Email string `db:"email,unique,notnull,size=64,fk=accounts.id,check=email<>''"`
+}
+
+func TestTableFromModel(t *testing.T) {
+	tableName, table, err := tableFromModel(testUser{})
+	if err != nil {
+		t.Fatalf("tableFromModel returned error: %v", err)
+	}
+	if tableName != "testuser" {
+		t.Errorf("tableName = %q, want %q", tableName, "testuser")
+	}
+	id, ok := table.columns["id"]
+	if !ok || !id.constraints.primaryKey {
+		t.Errorf("id column = %+v, ok=%v, want primaryKey", id, ok)
+	}
+	email, ok := table.columns["email"]
+	if !ok {
+		t.Fatalf("email column missing")
+	}
+	if email.columnType.size != 64 {
+		t.Errorf("email size = %d, want 64", email.columnType.size)
+	}
+	if !email.constraints.foreignKey || email.constraints.foreignKeyReference != "accounts.id" {
+		t.Errorf("email foreign key = %+v, want foreignKey with reference accounts.id", email.constraints)
+	}
+	if !email.constraints.check || email.constraints.checkExpression != "email<>''" {
+		t.Errorf("email check = %+v, want check with expression email<>''", email.constraints)
+	}
+}
+
+func TestTableFromModelRejectsNonStruct(t *testing.T) {
+	if _, _, err := tableFromModel("not a struct"); err == nil {
+		t.Error("expected error for non-struct model, got nil")
+	}
+}
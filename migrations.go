@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// migrationsLockID is the pg_advisory_lock key guarding MigrateUp/MigrateDown
+// so two instances of a program can't apply the same migration twice.
+const migrationsLockID int64 = 727480921
+
+// migration is one versioned, reversible schema change. up and down run
+// inside a transaction; sql and downSQL, when set (migrations loaded from a
+// directory), are the literal statements they execute and are what DryRun
+// prints.
+type migration struct {
+	version int
+	up      func(ctx context.Context, tx pgx.Tx) error
+	down    func(ctx context.Context, tx pgx.Tx) error
+	sql     string
+	downSQL string
+}
+
+// AddMigration registers a Go-defined, versioned up/down migration. Versions
+// must be unique but may be added in any order; MigrateUp/MigrateDown apply
+// them in version order regardless of registration order.
+func (db *database) AddMigration(version int, up, down func(ctx context.Context, tx pgx.Tx) error) error {
+	for _, existing := range db.migrations {
+		if existing.version == version {
+			return fmt.Errorf("migration version %d is already registered", version)
+		}
+	}
+	db.migrations = append(db.migrations, migration{version: version, up: up, down: down})
+	return nil
+}
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_.*\.(up|down)\.sql$`)
+
+// LoadMigrationsDir registers every paired "NNNN_name.up.sql" /
+// "NNNN_name.down.sql" file in dir as a migration whose up/down simply
+// execute the file's contents inside the migration transaction.
+func (db *database) LoadMigrationsDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	ups := make(map[int]string)
+	downs := make(map[int]string)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if match[2] == "up" {
+			ups[version] = string(contents)
+		} else {
+			downs[version] = string(contents)
+		}
+	}
+	for version, upSQL := range ups {
+		downSQL, hasDown := downs[version]
+		if !hasDown {
+			return fmt.Errorf("migration %d has an up.sql file but no matching down.sql file in %s", version, dir)
+		}
+		upSQL, downSQL := upSQL, downSQL
+		if err := db.AddMigration(version,
+			func(ctx context.Context, tx pgx.Tx) error { _, err := tx.Exec(ctx, upSQL); return err },
+			func(ctx context.Context, tx pgx.Tx) error { _, err := tx.Exec(ctx, downSQL); return err },
+		); err != nil {
+			return err
+		}
+		db.migrations[len(db.migrations)-1].sql = upSQL
+		db.migrations[len(db.migrations)-1].downSQL = downSQL
+	}
+	for version := range downs {
+		if _, hasUp := ups[version]; !hasUp {
+			return fmt.Errorf("migration %d has a down.sql file but no matching up.sql file in %s", version, dir)
+		}
+	}
+	return nil
+}
+
+// ensureMigrationsTable creates the schema_migrations bookkeeping table used
+// to record which versions have already been applied.
+func (db *database) ensureMigrationsTable(ctx context.Context) error {
+	_, err := db.connection.Exec(ctx,
+		"CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, applied_at TIMESTAMPTZ NOT NULL DEFAULT now());")
+	return err
+}
+
+func (db *database) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	rows, err := db.connection.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+func (db *database) sortedMigrations() []migration {
+	sorted := append([]migration(nil), db.migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].version < sorted[j].version })
+	return sorted
+}
+
+// withMigrationLock runs fn while holding the module's pg_advisory_lock, so
+// concurrent instances of a program can't apply migrations at the same time.
+func (db *database) withMigrationLock(ctx context.Context, fn func() error) error {
+	// Advisory locks are held per session, so lock and unlock must run on
+	// the same pooled connection.
+	conn, err := db.connection.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID); err != nil {
+		return err
+	}
+	defer conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", migrationsLockID)
+	return fn()
+}
+
+// MigrateUp applies every registered migration whose version is not yet
+// recorded in schema_migrations, in version order, each inside its own
+// transaction that also records it. When db.DryRun is set, it prints each
+// pending migration instead of applying it; the schema_migrations table is
+// still created if missing.
+func (db *database) MigrateUp(ctx context.Context) error {
+	if db.connection == nil {
+		return errors.New("database is not connected")
+	}
+	if err := db.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+	return db.withMigrationLock(ctx, func() error {
+		applied, err := db.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range db.sortedMigrations() {
+			if applied[m.version] {
+				continue
+			}
+			if db.DryRun {
+				fmt.Printf("-- would apply migration %d\n%s\n", m.version, describeMigration(m.sql))
+				continue
+			}
+			m := m
+			step := func(ctx context.Context, tx pgx.Tx) error {
+				if err := m.up(ctx, tx); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version)
+				return err
+			}
+			if err := db.runMigrationTx(ctx, step); err != nil {
+				return fmt.Errorf("migration %d: %w", m.version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// MigrateDown reverts applied migrations, newest first, down to but not
+// including target. When db.DryRun is set, it prints each migration it would
+// revert instead of reverting it; the schema_migrations table is still
+// created if missing.
+func (db *database) MigrateDown(ctx context.Context, target int) error {
+	if db.connection == nil {
+		return errors.New("database is not connected")
+	}
+	if err := db.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+	return db.withMigrationLock(ctx, func() error {
+		applied, err := db.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+		sorted := db.sortedMigrations()
+		for i := len(sorted) - 1; i >= 0; i-- {
+			m := sorted[i]
+			if m.version <= target || !applied[m.version] {
+				continue
+			}
+			if db.DryRun {
+				fmt.Printf("-- would revert migration %d\n%s\n", m.version, describeMigration(m.downSQL))
+				continue
+			}
+			step := func(ctx context.Context, tx pgx.Tx) error {
+				if err := m.down(ctx, tx); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.version)
+				return err
+			}
+			if err := db.runMigrationTx(ctx, step); err != nil {
+				return fmt.Errorf("migration %d: %w", m.version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// runMigrationTx runs step inside a transaction, committing on success and
+// rolling back on error.
+func (db *database) runMigrationTx(ctx context.Context, step func(ctx context.Context, tx pgx.Tx) error) error {
+	tx, err := db.connection.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := step(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func describeMigration(sql string) string {
+	if sql != "" {
+		return sql
+	}
+	return "(Go-defined migration, see the AddMigration call site)"
+}
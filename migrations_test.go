@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMigrationFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestLoadMigrationsDirRequiresMatchingDown(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrationFile(t, dir, "0001_create_users.up.sql", "CREATE TABLE users (id BIGINT PRIMARY KEY);")
+
+	db := database{}
+	if err := db.LoadMigrationsDir(dir); err == nil {
+		t.Fatal("expected error for an up.sql file with no matching down.sql, got nil")
+	}
+}
+
+func TestLoadMigrationsDirRequiresMatchingUp(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrationFile(t, dir, "0001_create_users.down.sql", "DROP TABLE users;")
+
+	db := database{}
+	if err := db.LoadMigrationsDir(dir); err == nil {
+		t.Fatal("expected error for a down.sql file with no matching up.sql, got nil")
+	}
+}
+
+func TestLoadMigrationsDirRegistersPairedMigrations(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrationFile(t, dir, "0001_create_users.up.sql", "CREATE TABLE users (id BIGINT PRIMARY KEY);")
+	writeMigrationFile(t, dir, "0001_create_users.down.sql", "DROP TABLE users;")
+
+	db := database{}
+	if err := db.LoadMigrationsDir(dir); err != nil {
+		t.Fatalf("LoadMigrationsDir returned error: %v", err)
+	}
+	if len(db.migrations) != 1 || db.migrations[0].version != 1 {
+		t.Fatalf("migrations = %+v, want one migration at version 1", db.migrations)
+	}
+}
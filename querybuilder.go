@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// queryExecutor is the subset of *pgxpool.Pool and pgx.Tx that the query
+// builder needs, so a QueryBuilder can run against either a pooled
+// connection or an in-flight transaction.
+type queryExecutor interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// QueryBuilder builds a single parameterized statement against the owning
+// database's dialect. Obtain one with database.Table or Tx.Table; it is not
+// meant to be reused across statements.
+type QueryBuilder struct {
+	exec    queryExecutor
+	dialect Dialect
+	table   string
+
+	selectColumns []string
+	joins         []string
+	whereClauses  []string
+	whereArgs     []any
+	limit         int
+	hasLimit      bool
+
+	isInsert     bool
+	insertValues map[string]any
+	returning    []string
+}
+
+// Table starts a query builder for the given table using db's pooled
+// connection.
+func (db *database) Table(table string) *QueryBuilder {
+	var exec queryExecutor
+	if db.connection != nil {
+		exec = db.connection
+	}
+	return &QueryBuilder{exec: exec, dialect: db.dialect, table: table}
+}
+
+// Select restricts the columns returned by a subsequent Scan; with no
+// columns given, Scan selects "*".
+func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
+	qb.selectColumns = columns
+	return qb
+}
+
+// Where adds an "AND"-joined condition. condition may contain "?"
+// placeholders, filled positionally by args and rendered in the owning
+// database's dialect (e.g. "$1" for Postgres, "?" for MySQL/SQLite).
+func (qb *QueryBuilder) Where(condition string, args ...any) *QueryBuilder {
+	qb.whereClauses = append(qb.whereClauses, condition)
+	qb.whereArgs = append(qb.whereArgs, args...)
+	return qb
+}
+
+// Join adds a "JOIN table ON on" clause. table is quoted as an identifier;
+// on is a raw condition and is not quoted, matching Where.
+func (qb *QueryBuilder) Join(table, on string) *QueryBuilder {
+	qb.joins = append(qb.joins, fmt.Sprintf("JOIN %s ON %s", qb.dialect.Quote(table), on))
+	return qb
+}
+
+// Limit caps the number of rows a subsequent Scan returns.
+func (qb *QueryBuilder) Limit(n int) *QueryBuilder {
+	qb.limit = n
+	qb.hasLimit = true
+	return qb
+}
+
+// Insert switches the builder into insert mode; call Values (and optionally
+// Returning) before Scan.
+func (qb *QueryBuilder) Insert() *QueryBuilder {
+	qb.isInsert = true
+	return qb
+}
+
+// Values sets the column/value pairs for an Insert().
+func (qb *QueryBuilder) Values(values map[string]any) *QueryBuilder {
+	qb.insertValues = values
+	return qb
+}
+
+// Returning adds a Postgres RETURNING clause to an Insert(), whose values
+// Scan reads back into dest.
+func (qb *QueryBuilder) Returning(columns ...string) *QueryBuilder {
+	qb.returning = columns
+	return qb
+}
+
+// rewritePlaceholders replaces each "?" in condition with the dialect's
+// positional placeholder, starting at startIndex, and returns the rewritten
+// string and the next unused index.
+func rewritePlaceholders(dialect Dialect, condition string, startIndex int) (string, int) {
+	var rewritten strings.Builder
+	index := startIndex
+	for _, r := range condition {
+		if r != '?' {
+			rewritten.WriteRune(r)
+			continue
+		}
+		rewritten.WriteString(dialect.Placeholder(index))
+		index++
+	}
+	return rewritten.String(), index
+}
+
+func (qb *QueryBuilder) buildSelectSQL() (string, []any) {
+	columns := "*"
+	if len(qb.selectColumns) > 0 {
+		quoted := make([]string, len(qb.selectColumns))
+		for i, column := range qb.selectColumns {
+			quoted[i] = qb.dialect.Quote(column)
+		}
+		columns = strings.Join(quoted, ", ")
+	}
+	sqlString := fmt.Sprintf("SELECT %s FROM %s", columns, qb.dialect.Quote(qb.table))
+	if len(qb.joins) > 0 {
+		sqlString += " " + strings.Join(qb.joins, " ")
+	}
+	if len(qb.whereClauses) > 0 {
+		var rewritten []string
+		index := 1
+		for _, clause := range qb.whereClauses {
+			var rewrittenClause string
+			rewrittenClause, index = rewritePlaceholders(qb.dialect, clause, index)
+			rewritten = append(rewritten, rewrittenClause)
+		}
+		sqlString += " WHERE " + strings.Join(rewritten, " AND ")
+	}
+	if qb.hasLimit {
+		sqlString += fmt.Sprintf(" LIMIT %d", qb.limit)
+	}
+	return sqlString, qb.whereArgs
+}
+
+func (qb *QueryBuilder) buildInsertSQL() (string, []any) {
+	var keys []string
+	var placeholders []string
+	var args []any
+	for key, value := range qb.insertValues {
+		keys = append(keys, qb.dialect.Quote(key))
+		args = append(args, value)
+		placeholders = append(placeholders, qb.dialect.Placeholder(len(args)))
+	}
+	sqlString := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qb.dialect.Quote(qb.table), strings.Join(keys, ", "), strings.Join(placeholders, ", "))
+	if len(qb.returning) > 0 {
+		sqlString += " RETURNING " + strings.Join(qb.returning, ", ")
+	}
+	return sqlString, args
+}
+
+// Scan executes the built statement. For a plain Table(...) builder, dest
+// must be a pointer to a slice of structs and is filled via reflection on
+// `db:""` tags (falling back to the lowercased field name). For an
+// Insert()...Returning(...) builder, dest is scanned directly from the
+// single returned row, matching pgx's Row.Scan.
+func (qb *QueryBuilder) Scan(dest any) error {
+	if qb.exec == nil {
+		return errors.New("database is not connected")
+	}
+	if qb.isInsert {
+		sqlString, args := qb.buildInsertSQL()
+		if len(qb.returning) == 0 {
+			_, err := qb.exec.Exec(context.Background(), sqlString, args...)
+			return err
+		}
+		return qb.exec.QueryRow(context.Background(), sqlString, args...).Scan(dest)
+	}
+	sqlString, args := qb.buildSelectSQL()
+	rows, err := qb.exec.Query(context.Background(), sqlString, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return scanRowsInto(rows, dest)
+}
+
+// scannableInto reports whether a value of type source may be scanned into
+// a struct field of type target: either an exact type match, or numeric
+// widening within the same family (integer-to-integer, float-to-float).
+// reflect.Type.ConvertibleTo is deliberately not used here, since it also
+// allows integer-to-string conversion, which is Go's rune conversion and
+// would silently turn a numeric column value into a one-character string.
+func scannableInto(source, target reflect.Type) bool {
+	if source == target {
+		return true
+	}
+	sourceKind, targetKind := source.Kind(), target.Kind()
+	if isIntegerKind(sourceKind) && isIntegerKind(targetKind) {
+		return true
+	}
+	if isFloatKind(sourceKind) && isFloatKind(targetKind) {
+		return true
+	}
+	return false
+}
+
+// scanRowsInto scans every row of rows into dest, a pointer to a slice of
+// structs, matching each returned column to a field by its `db:""` tag name
+// or, absent a tag, its lowercased field name.
+func scanRowsInto(rows pgx.Rows, dest any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return errors.New("Scan destination must be a pointer to a slice of structs")
+	}
+	sliceValue := destValue.Elem()
+	elemType := sliceValue.Type().Elem()
+
+	fieldIndexByColumn := make(map[string]int)
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		name := strings.ToLower(field.Name)
+		if tag, hasTag := field.Tag.Lookup("db"); hasTag {
+			if tagName, _, _, _, _ := parseDBTag(tag); tagName != "" {
+				name = tagName
+			}
+		}
+		fieldIndexByColumn[name] = i
+	}
+
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(elemType).Elem()
+		for i, field := range rows.FieldDescriptions() {
+			fieldIndex, known := fieldIndexByColumn[field.Name]
+			if !known || values[i] == nil {
+				continue
+			}
+			fieldValue := elem.Field(fieldIndex)
+			sourceValue := reflect.ValueOf(values[i])
+			if !scannableInto(sourceValue.Type(), fieldValue.Type()) {
+				return fmt.Errorf("column %q: cannot scan %T into struct field of type %s", field.Name, values[i], fieldValue.Type())
+			}
+			fieldValue.Set(sourceValue.Convert(fieldValue.Type()))
+		}
+		sliceValue.Set(reflect.Append(sliceValue, elem))
+	}
+	return rows.Err()
+}
+
+// Tx wraps a pgx.Tx so QueryBuilder statements started from it participate
+// in the same transaction.
+type Tx struct {
+	tx      pgx.Tx
+	dialect Dialect
+}
+
+// Table starts a query builder for the given table that runs inside this
+// transaction.
+func (tx *Tx) Table(table string) *QueryBuilder {
+	return &QueryBuilder{exec: tx.tx, dialect: tx.dialect, table: table}
+}
+
+// Tx runs fn inside a new transaction, committing if fn returns nil and
+// rolling back otherwise.
+func (db *database) Tx(fn func(tx *Tx) error) error {
+	if db.connection == nil {
+		return errors.New("database is not connected")
+	}
+	ctx := context.Background()
+	pgxTx, err := db.connection.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer pgxTx.Rollback(ctx)
+	if err := fn(&Tx{tx: pgxTx, dialect: db.dialect}); err != nil {
+		return err
+	}
+	return pgxTx.Commit(ctx)
+}
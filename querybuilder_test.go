@@ -0,0 +1,99 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRewritePlaceholdersPostgres(t *testing.T) {
+	got, next := rewritePlaceholders(postgresDialect{}, "username = ? AND age > ?", 1)
+	want := "username = $1 AND age > $2"
+	if got != want {
+		t.Errorf("rewritePlaceholders = %q, want %q", got, want)
+	}
+	if next != 3 {
+		t.Errorf("next index = %d, want 3", next)
+	}
+}
+
+func TestRewritePlaceholdersMySQL(t *testing.T) {
+	got, next := rewritePlaceholders(mysqlDialect{}, "username = ?", 1)
+	if got != "username = ?" {
+		t.Errorf("rewritePlaceholders = %q, want %q", got, "username = ?")
+	}
+	if next != 2 {
+		t.Errorf("next index = %d, want 2", next)
+	}
+}
+
+func TestBuildSelectSQL(t *testing.T) {
+	qb := (&database{dialect: postgresDialect{}}).Table("users").
+		Select("id", "username").
+		Where("username = ?", "alice").
+		Where("age > ?", 18).
+		Join("orders", "orders.user_id = users.id").
+		Limit(10)
+
+	sqlString, args := qb.buildSelectSQL()
+	want := `SELECT "id", "username" FROM "users" JOIN "orders" ON orders.user_id = users.id WHERE username = $1 AND age > $2 LIMIT 10`
+	if sqlString != want {
+		t.Errorf("buildSelectSQL sql = %q, want %q", sqlString, want)
+	}
+	if len(args) != 2 || args[0] != "alice" || args[1] != 18 {
+		t.Errorf("buildSelectSQL args = %v, want [alice 18]", args)
+	}
+}
+
+func TestBuildSelectSQLDefaultsToStar(t *testing.T) {
+	qb := (&database{dialect: postgresDialect{}}).Table("users")
+	sqlString, _ := qb.buildSelectSQL()
+	if sqlString != `SELECT * FROM "users"` {
+		t.Errorf("buildSelectSQL sql = %q, want %q", sqlString, `SELECT * FROM "users"`)
+	}
+}
+
+func TestBuildInsertSQL(t *testing.T) {
+	qb := (&database{dialect: postgresDialect{}}).Table("users").
+		Insert().
+		Values(map[string]any{"username": "alice"}).
+		Returning("id")
+
+	sqlString, args := qb.buildInsertSQL()
+	want := `INSERT INTO "users" ("username") VALUES ($1) RETURNING id`
+	if sqlString != want {
+		t.Errorf("buildInsertSQL sql = %q, want %q", sqlString, want)
+	}
+	if len(args) != 1 || args[0] != "alice" {
+		t.Errorf("buildInsertSQL args = %v, want [alice]", args)
+	}
+}
+
+func TestScanErrorsWhenNotConnected(t *testing.T) {
+	db := &database{dialect: postgresDialect{}}
+	var dest []struct{ ID int }
+	err := db.Table("users").Scan(&dest)
+	if err == nil {
+		t.Fatal("Scan on an unconnected database returned nil error, want one")
+	}
+}
+
+func TestScannableIntoRejectsIntToString(t *testing.T) {
+	if scannableInto(reflect.TypeOf(int32(65)), reflect.TypeOf("")) {
+		t.Error("scannableInto allowed int32 -> string, want rejected (Go's rune conversion would turn 65 into \"A\")")
+	}
+}
+
+func TestScannableIntoAllowsNumericWidening(t *testing.T) {
+	if !scannableInto(reflect.TypeOf(int32(0)), reflect.TypeOf(int64(0))) {
+		t.Error("scannableInto rejected int32 -> int64, want allowed")
+	}
+	if !scannableInto(reflect.TypeOf(float32(0)), reflect.TypeOf(float64(0))) {
+		t.Error("scannableInto rejected float32 -> float64, want allowed")
+	}
+}
+
+func TestScannableIntoAllowsExactMatch(t *testing.T) {
+	if !scannableInto(reflect.TypeOf(""), reflect.TypeOf("")) {
+		t.Error("scannableInto rejected string -> string, want allowed")
+	}
+}